@@ -2,12 +2,9 @@ package curator
 
 import (
 	"github.com/cenkalti/backoff"
-	"github.com/davecgh/go-spew/spew"
 	"github.com/talbright/go-zookeeper/zk"
 
 	"errors"
-	"fmt"
-	"strings"
 	"sync"
 	"time"
 )
@@ -30,17 +27,53 @@ var ErrInvalidPath = errors.New("provided path is invalid")
 //Client connects to and interacts with zk.
 type Client struct {
 	*zk.Conn
-	mutex *sync.RWMutex
+	mutex  *sync.RWMutex
+	logger Logger
+
+	settings    *Settings
+	connOptions []zk.ConnOption
+
+	sessionMu      sync.Mutex
+	onConnected    []SessionCallback
+	onHasSession   []SessionCallback
+	onDisconnected []SessionCallback
+	onExpired      []SessionCallback
+	onAuthFailed   []SessionCallback
+	onReconnect    []SessionCallback
+	superviseStop  chan struct{}
+	superviseDone  chan struct{}
 }
 
+//ClientOption configures optional behavior on a Client, applied in NewClient.
+type ClientOption func(*Client)
+
 //NewClient creates a client that can interact with zk
-func NewClient() *Client {
-	return &Client{mutex: &sync.RWMutex{}}
+func NewClient(options ...ClientOption) *Client {
+	c := &Client{mutex: &sync.RWMutex{}, logger: NullLogger{}}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+//conn returns a stable snapshot of the underlying zk connection, guarding
+//against Supervise's reconnect loop swapping the embedded *zk.Conn out from
+//under a caller that reaches zk through more than one promoted call.
+func (c *Client) conn() *zk.Conn {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.Conn
 }
 
 //Connect creates a connection to zookeeper for the client
 func (c *Client) Connect(settings *Settings, options ...zk.ConnOption) (evnt <-chan zk.Event, err error) {
-	c.Conn, evnt, err = zk.Connect(settings.ZkServers, settings.ZkSessionTimeout, options...)
+	c.settings = settings
+	c.connOptions = options
+	var conn *zk.Conn
+	conn, evnt, err = zk.Connect(settings.ZkServers, settings.ZkSessionTimeout, options...)
+	c.mutex.Lock()
+	c.Conn = conn
+	c.mutex.Unlock()
 	if settings.ZkWaitForSession && err == nil {
 		timeout := make(chan bool, 1)
 		if settings.ZkWaitForSessionTimeout > 0 {
@@ -52,6 +85,7 @@ func (c *Client) Connect(settings *Settings, options ...zk.ConnOption) (evnt <-c
 		for {
 			select {
 			case <-timeout:
+				c.logger.Warn("timed out waiting for zookeeper session", "servers", settings.ZkServers)
 				c.Conn.Close()
 				err = ErrConnectionTimedOut
 				return
@@ -59,6 +93,7 @@ func (c *Client) Connect(settings *Settings, options ...zk.ConnOption) (evnt <-c
 				if event.Type == zk.EventSession {
 					switch event.State {
 					case zk.StateHasSession:
+						c.logger.Info("zookeeper session established", "servers", settings.ZkServers)
 						return
 					}
 				}
@@ -68,52 +103,26 @@ func (c *Client) Connect(settings *Settings, options ...zk.ConnOption) (evnt <-c
 	return
 }
 
-/*
-CreatePath will create the full path in zookeeper (emulates 'mkdir -p'). Each
-node will be assigned the same data and acl permissions. Only non-ephemeral
-nodes can have children.
-
-The path parameter must begin with '/'
-*/
-func (c *Client) CreatePath(path string, data []byte, acl []zk.ACL) error {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-	if !strings.HasPrefix(path, "/") {
-		return ErrInvalidPath
-	}
-	segments := strings.Split(path, "/")
-	segments = segments[1:len(segments)]
-	var slice []string
-	for i := range segments {
-		slice = append(slice, segments[i])
-		segment := fmt.Sprintf("/%s", strings.Join(slice, "/"))
-		exists, _, err := c.Exists(segment)
-		if err != nil {
-			return err
-		}
-		if exists != true {
-			_, err := c.Create(segment, data, 0, acl)
-			if err != nil {
-				return err
-			}
-		}
-	}
-	return nil
-}
-
 func (c *Client) WaitToExist(path string, maxWaitTime time.Duration) (err error) {
+	path = NormalizePath(path)
+	if err := ValidatePath(path); err != nil {
+		return err
+	}
 	retryCount := 0
 	operation := func() error {
-		spew.Printf("[curator] Client#WaitToExist: path %v (retry=%d)\n", path, retryCount)
-		exists, _, err := c.Exists(path)
-		if err == nil && !exists {
-			err = ErrInvalidPath
+		c.logger.Debug("waiting for path to exist", "path", path, "retry", retryCount)
+		exists, _, opErr := c.conn().Exists(path)
+		if opErr == nil && !exists {
+			opErr = ErrPathNotFound
 		}
 		retryCount++
-		return err
+		return opErr
 	}
 	expBackoff := backoff.NewExponentialBackOff()
 	expBackoff.MaxElapsedTime = maxWaitTime
-	backoff.Retry(operation, expBackoff)
+	err = backoff.Retry(operation, expBackoff)
+	if err != nil {
+		c.logger.Error("gave up waiting for path to exist", "path", path, "retries", retryCount, "error", err)
+	}
 	return err
 }
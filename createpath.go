@@ -0,0 +1,134 @@
+package curator
+
+import (
+	"github.com/talbright/go-zookeeper/zk"
+
+	"fmt"
+	"strings"
+)
+
+//maxMultiDepth bounds how many segments CreatePath will bundle into a
+//single zk.Multi transaction. Deeper paths fall back to the original
+//sequential exists-then-create behaviour, since a Multi batching one op per
+//segment can exceed the server's jute.maxbuffer for very deep trees.
+const maxMultiDepth = 32
+
+//PathSegment describes one node to create as part of a CreatePath call: its
+//full path (not just the final component), the data and ACL it should be
+//created with, and any zk create flags (e.g. zk.FlagEphemeral on the leaf).
+type PathSegment struct {
+	Path  string
+	Data  []byte
+	ACL   []zk.ACL
+	Flags int32
+}
+
+//NewPathSegments splits path into the ordered list of PathSegment needed to
+//create it with 'mkdir -p' semantics: every intermediate node is created
+//empty, and only the final (leaf) segment receives data and flags.
+func NewPathSegments(path string, data []byte, acl []zk.ACL, flags int32) []PathSegment {
+	path = NormalizePath(path)
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	segments := make([]PathSegment, len(parts))
+	var slice []string
+	for i, part := range parts {
+		slice = append(slice, part)
+		segments[i] = PathSegment{Path: fmt.Sprintf("/%s", strings.Join(slice, "/")), ACL: acl}
+	}
+	segments[len(segments)-1].Data = data
+	segments[len(segments)-1].Flags = flags
+	return segments
+}
+
+/*
+CreatePath creates every node described by segments, in order, emulating
+'mkdir -p'. Segments whose path already exists are skipped; the remaining
+(always contiguous, since a parent can't be missing while its child exists)
+suffix is created as a single zk.Multi transaction so it either all comes
+into existence or none of it does, instead of racing other creators one
+Exists/Create round-trip at a time.
+
+Each segment carries its own data/ACL/flags, so parents can be created
+empty while only the leaf gets the real payload and flags.
+
+Every segment's Path is first rewritten by NormalizePath (which also
+supplies a missing leading '/') and then checked with ValidatePath; an
+invalid path fails the whole call before anything is created.
+*/
+func (c *Client) CreatePath(segments []PathSegment) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for i := range segments {
+		segments[i].Path = NormalizePath(segments[i].Path)
+		if err := ValidatePath(segments[i].Path); err != nil {
+			return err
+		}
+	}
+
+	missing := segments[:0]
+	for i, s := range segments {
+		exists, _, err := c.Exists(s.Path)
+		if err != nil {
+			c.logger.Error("failed to check existence of path segment", "path", s.Path, "error", err)
+			return err
+		}
+		if !exists {
+			missing = segments[i:]
+			break
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if len(missing) <= maxMultiDepth {
+		err := c.createPathMulti(missing)
+		if err == nil {
+			return nil
+		}
+		switch err {
+		case zk.ErrAPIError:
+			c.logger.Warn("zk.Multi unavailable, falling back to sequential create", "path", missing[len(missing)-1].Path, "error", err)
+		case zk.ErrNodeExists:
+			//A concurrent creator won the race between our existence scan and
+			//this Multi call, so one of the ops in the transaction failed and
+			//the whole batch was rolled back. createPathSequential re-checks
+			//each segment's existence before creating it, so it tolerates
+			//whatever the other creator already put in place.
+			c.logger.Debug("lost the create race to a concurrent creator, falling back to sequential create", "path", missing[len(missing)-1].Path, "error", err)
+		default:
+			c.logger.Error("failed to create path transactionally", "path", missing[len(missing)-1].Path, "error", err)
+			return err
+		}
+	}
+	return c.createPathSequential(missing)
+}
+
+func (c *Client) createPathMulti(segments []PathSegment) error {
+	ops := make([]interface{}, len(segments))
+	for i, s := range segments {
+		ops[i] = &zk.CreateRequest{Path: s.Path, Data: s.Data, Acl: s.ACL, Flags: s.Flags}
+	}
+	_, err := c.Multi(ops...)
+	return err
+}
+
+func (c *Client) createPathSequential(segments []PathSegment) error {
+	for _, s := range segments {
+		exists, _, err := c.Exists(s.Path)
+		if err != nil {
+			c.logger.Error("failed to check existence of path segment", "path", s.Path, "error", err)
+			return err
+		}
+		if exists {
+			continue
+		}
+		c.logger.Debug("creating path segment", "path", s.Path)
+		if _, err := c.Create(s.Path, s.Data, s.Flags, s.ACL); err != nil {
+			c.logger.Error("failed to create path segment", "path", s.Path, "error", err)
+			return err
+		}
+	}
+	return nil
+}
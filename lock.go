@@ -0,0 +1,274 @@
+package curator
+
+import (
+	"github.com/talbright/go-zookeeper/zk"
+
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const lockNodePrefix = "lock-"
+
+//ErrLockNotHeld occurs when Release is called on a Lock that is not held.
+var ErrLockNotHeld = errors.New("lock is not held")
+
+//zkOps is the subset of *zk.Conn that Lock's acquisition logic needs. It
+//exists so that logic can be exercised in tests against a fake
+//implementation instead of a live zk connection.
+type zkOps interface {
+	Create(path string, data []byte, flags int32, acl []zk.ACL) (string, error)
+	Delete(path string, version int32) error
+	Children(path string) ([]string, *zk.Stat, error)
+	ExistsW(path string) (bool, *zk.Stat, <-chan zk.Event, error)
+}
+
+//Lock implements the standard ZooKeeper sequential-ephemeral lock recipe:
+//each contender creates an ephemeral sequential child of path, and holds the
+//lock when its child is the lowest sequence number. Contenders that are not
+//lowest watch the child immediately below them and wake up when it is
+//removed. A Lock is reentrant: nested Acquire/Release calls on the same Lock
+//only touch zookeeper on the outermost pair.
+type Lock struct {
+	client *Client
+	path   string
+	acl    []zk.ACL
+	ops    zkOps
+
+	mutex    sync.Mutex
+	count    int
+	nodePath string
+}
+
+//NewLock creates a Lock that contends for ownership of path using client.
+//path is created (without the sequential suffix) if it does not already
+//exist.
+func NewLock(client *Client, path string, acl []zk.ACL) *Lock {
+	return &Lock{client: client, path: path, acl: acl}
+}
+
+//zk returns the zkOps implementation this Lock should use: ops, if a test
+//has set one, otherwise the client's own connection.
+func (l *Lock) zk() zkOps {
+	if l.ops != nil {
+		return l.ops
+	}
+	return l.client.conn()
+}
+
+//TryAcquire makes a single, non-blocking attempt to acquire the lock: it
+//creates our sequential node (if we don't already have one) and checks
+//whether it is the lowest sequence number. If it isn't, the attempt is
+//abandoned immediately - our node is deleted rather than left in the queue
+//to be watched later - and TryAcquire returns false with no error. Call
+//Acquire instead if you want to wait your turn.
+func (l *Lock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.count > 0 {
+		l.count++
+		return true, nil
+	}
+	acquired, err := l.attempt(ctx, false)
+	if err != nil {
+		return false, err
+	}
+	if acquired {
+		l.count = 1
+	}
+	return acquired, nil
+}
+
+//Acquire blocks until the lock is held or ctx is done.
+func (l *Lock) Acquire(ctx context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.count > 0 {
+		l.count++
+		return nil
+	}
+	acquired, err := l.attempt(ctx, true)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return ctx.Err()
+	}
+	l.count = 1
+	return nil
+}
+
+//Release decrements the reentrant counter, deleting our znode once it
+//reaches zero. It returns ErrLockNotHeld if the lock is not currently held
+//by this Lock.
+func (l *Lock) Release() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.count == 0 {
+		return ErrLockNotHeld
+	}
+	l.count--
+	if l.count > 0 {
+		return nil
+	}
+	nodePath := l.nodePath
+	l.nodePath = ""
+	return l.zk().Delete(nodePath, -1)
+}
+
+//attempt performs (or resumes) a single acquisition attempt. If wait is
+//false it gives up - abandoning our node - the first time we are not the
+//lowest sequence number, instead of watching the predecessor. The caller
+//must hold l.mutex.
+func (l *Lock) attempt(ctx context.Context, wait bool) (bool, error) {
+	if l.nodePath == "" {
+		if err := l.client.CreatePath(NewPathSegments(l.path, nil, l.acl, 0)); err != nil {
+			return false, err
+		}
+		nodePath, err := l.zk().Create(
+			fmt.Sprintf("%s/%s", l.path, lockNodePrefix),
+			[]byte{},
+			zk.FlagEphemeral|zk.FlagSequence,
+			l.acl,
+		)
+		if err != nil {
+			return false, err
+		}
+		l.nodePath = nodePath
+	}
+
+	for {
+		lowest, predecessor, err := l.checkLowest()
+		if err != nil {
+			l.abandon()
+			return false, err
+		}
+		if lowest {
+			return true, nil
+		}
+		if !wait {
+			l.abandon()
+			return false, nil
+		}
+		exists, _, watch, err := l.zk().ExistsW(predecessor)
+		if err != nil {
+			l.abandon()
+			return false, err
+		}
+		if !exists {
+			continue
+		}
+		select {
+		case <-watch:
+			continue
+		case <-ctx.Done():
+			l.abandon()
+			return false, nil
+		}
+	}
+}
+
+//checkLowest reports whether our node is currently the lowest sequence
+//number under l.path; if it isn't, it also returns the full path of the
+//immediate predecessor to watch.
+func (l *Lock) checkLowest() (lowest bool, predecessor string, err error) {
+	children, _, err := l.zk().Children(l.path)
+	if err != nil {
+		return false, "", err
+	}
+	sort.Strings(children)
+	myName := l.nodePath[strings.LastIndex(l.nodePath, "/")+1:]
+	index := sort.SearchStrings(children, myName)
+	if index == 0 {
+		return true, "", nil
+	}
+	return false, fmt.Sprintf("%s/%s", l.path, children[index-1]), nil
+}
+
+//abandon deletes our znode (if any) and clears l.nodePath, so a failed or
+//abandoned attempt doesn't leave a stale entry in the sequence for other
+//contenders to sort against, and so a later Release call can't mistake the
+//abandoned path for a held lock. The caller must hold l.mutex.
+func (l *Lock) abandon() {
+	if l.nodePath == "" {
+		return
+	}
+	nodePath := l.nodePath
+	l.nodePath = ""
+	if err := l.zk().Delete(nodePath, -1); err != nil {
+		l.client.Logger().Warn("unable to delete abandoned lock node", "path", nodePath, "error", err)
+	}
+}
+
+//LeaderLatch implements leader election on top of Lock: the contender
+//holding the lock is the leader, and Changes fires whenever that status
+//changes for this latch.
+type LeaderLatch struct {
+	lock    *Lock
+	mutex   sync.Mutex
+	leader  bool
+	changes chan bool
+}
+
+//NewLeaderLatch creates a LeaderLatch contending for leadership at path.
+func NewLeaderLatch(client *Client, path string, acl []zk.ACL) *LeaderLatch {
+	return &LeaderLatch{
+		lock:    NewLock(client, path, acl),
+		changes: make(chan bool, 1),
+	}
+}
+
+//Start blocks until this latch becomes the leader or ctx is done.
+func (ll *LeaderLatch) Start(ctx context.Context) error {
+	if err := ll.lock.Acquire(ctx); err != nil {
+		return err
+	}
+	ll.setLeader(true)
+	return nil
+}
+
+//Close releases leadership, if held. It is a no-op if this latch never
+//became the leader, e.g. because Start was never called or returned an
+//error.
+func (ll *LeaderLatch) Close() error {
+	if !ll.Leader() {
+		return nil
+	}
+	ll.setLeader(false)
+	return ll.lock.Release()
+}
+
+//Leader reports whether this latch currently holds leadership.
+func (ll *LeaderLatch) Leader() bool {
+	ll.mutex.Lock()
+	defer ll.mutex.Unlock()
+	return ll.leader
+}
+
+//Changes returns a channel that receives the new leadership state every
+//time it changes.
+func (ll *LeaderLatch) Changes() <-chan bool {
+	return ll.changes
+}
+
+func (ll *LeaderLatch) setLeader(leader bool) {
+	ll.mutex.Lock()
+	changed := ll.leader != leader
+	ll.leader = leader
+	ll.mutex.Unlock()
+	if !changed {
+		return
+	}
+	select {
+	case ll.changes <- leader:
+	default:
+		select {
+		case <-ll.changes:
+		default:
+		}
+		ll.changes <- leader
+	}
+}
@@ -0,0 +1,120 @@
+package curator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/talbright/go-zookeeper/zk"
+)
+
+//fakeZkOps is a minimal in-memory zkOps, just enough to drive Lock's
+//lowest/abandon logic without a live zk connection. Children is driven
+//directly from siblings rather than Create, since Lock.attempt only calls
+//Create when nodePath is unset.
+type fakeZkOps struct {
+	siblings []string
+	deleted  []string
+}
+
+func (f *fakeZkOps) Create(path string, data []byte, flags int32, acl []zk.ACL) (string, error) {
+	return path, nil
+}
+
+func (f *fakeZkOps) Delete(path string, version int32) error {
+	f.deleted = append(f.deleted, path)
+	return nil
+}
+
+func (f *fakeZkOps) Children(path string) ([]string, *zk.Stat, error) {
+	return f.siblings, nil, nil
+}
+
+func (f *fakeZkOps) ExistsW(path string) (bool, *zk.Stat, <-chan zk.Event, error) {
+	watch := make(chan zk.Event)
+	return true, nil, watch, nil
+}
+
+func TestLockCheckLowest(t *testing.T) {
+	ops := &fakeZkOps{siblings: []string{"lock-0000000000", "lock-0000000001"}}
+	l := &Lock{path: "/locks", ops: ops, nodePath: "/locks/lock-0000000000"}
+
+	lowest, predecessor, err := l.checkLowest()
+	if err != nil {
+		t.Fatalf("checkLowest returned error: %v", err)
+	}
+	if !lowest {
+		t.Fatalf("checkLowest = false, want true for the lowest sequence node")
+	}
+	if predecessor != "" {
+		t.Fatalf("checkLowest predecessor = %q, want empty when lowest", predecessor)
+	}
+
+	l.nodePath = "/locks/lock-0000000001"
+	lowest, predecessor, err = l.checkLowest()
+	if err != nil {
+		t.Fatalf("checkLowest returned error: %v", err)
+	}
+	if lowest {
+		t.Fatalf("checkLowest = true, want false when not the lowest sequence node")
+	}
+	if want := "/locks/lock-0000000000"; predecessor != want {
+		t.Fatalf("checkLowest predecessor = %q, want %q", predecessor, want)
+	}
+}
+
+func TestLockAttemptNotLowestNoWaitAbandons(t *testing.T) {
+	ops := &fakeZkOps{siblings: []string{"lock-0000000000", "lock-0000000001"}}
+	l := &Lock{path: "/locks", ops: ops, nodePath: "/locks/lock-0000000001"}
+
+	acquired, err := l.attempt(context.Background(), false)
+	if err != nil {
+		t.Fatalf("attempt returned error: %v", err)
+	}
+	if acquired {
+		t.Fatalf("attempt = true, want false when not the lowest sequence node")
+	}
+	if l.nodePath != "" {
+		t.Fatalf("attempt left nodePath = %q, want abandoned (empty)", l.nodePath)
+	}
+	if len(ops.deleted) != 1 || ops.deleted[0] != "/locks/lock-0000000001" {
+		t.Fatalf("attempt did not delete the abandoned node, deleted = %v", ops.deleted)
+	}
+}
+
+func TestLockAttemptLowestAcquires(t *testing.T) {
+	ops := &fakeZkOps{siblings: []string{"lock-0000000000"}}
+	l := &Lock{path: "/locks", ops: ops, nodePath: "/locks/lock-0000000000"}
+
+	acquired, err := l.attempt(context.Background(), false)
+	if err != nil {
+		t.Fatalf("attempt returned error: %v", err)
+	}
+	if !acquired {
+		t.Fatalf("attempt = false, want true for the lowest sequence node")
+	}
+	if l.nodePath == "" {
+		t.Fatalf("attempt abandoned the node on success")
+	}
+	if len(ops.deleted) != 0 {
+		t.Fatalf("attempt deleted the node on success: %v", ops.deleted)
+	}
+}
+
+func TestLockAbandonIsIdempotent(t *testing.T) {
+	ops := &fakeZkOps{}
+	l := &Lock{path: "/locks", ops: ops}
+
+	l.abandon()
+	if len(ops.deleted) != 0 {
+		t.Fatalf("abandon deleted with no nodePath set: %v", ops.deleted)
+	}
+
+	l.nodePath = "/locks/lock-0000000000"
+	l.abandon()
+	if len(ops.deleted) != 1 {
+		t.Fatalf("abandon did not delete the node, deleted = %v", ops.deleted)
+	}
+	if l.nodePath != "" {
+		t.Fatalf("abandon left nodePath = %q, want empty", l.nodePath)
+	}
+}
@@ -0,0 +1,36 @@
+package curator
+
+//Logger is implemented by anything that can receive structured, leveled log
+//output from the client. keyvals are passed as alternating key/value pairs,
+//mirroring the convention used by zap's sugared logger; the logrus adapter
+//converts them into a logrus.Fields map on each call.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+//Debug is a no-op; NullLogger also satisfies Logger so it can silence both
+//the zk connection logger and the curator client logger.
+func (NullLogger) Debug(msg string, keyvals ...interface{}) {}
+
+//Info is a no-op.
+func (NullLogger) Info(msg string, keyvals ...interface{}) {}
+
+//Warn is a no-op.
+func (NullLogger) Warn(msg string, keyvals ...interface{}) {}
+
+//Error is a no-op.
+func (NullLogger) Error(msg string, keyvals ...interface{}) {}
+
+//SetLogger replaces the client's logger. The default, set by NewClient, is
+//NullLogger.
+func (c *Client) SetLogger(logger Logger) {
+	c.logger = logger
+}
+
+//Logger returns the client's current logger.
+func (c *Client) Logger() Logger {
+	return c.logger
+}
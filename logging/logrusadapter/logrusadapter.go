@@ -0,0 +1,44 @@
+//Package logrusadapter adapts a logrus.FieldLogger to curator.Logger.
+package logrusadapter
+
+import (
+	curator "github.com/talbright/go-curator"
+
+	"github.com/sirupsen/logrus"
+)
+
+//Logger wraps a logrus.FieldLogger, converting keyvals into logrus.Fields
+//for each call.
+type Logger struct {
+	logrus.FieldLogger
+}
+
+//New wraps l as a curator.Logger.
+func New(l logrus.FieldLogger) *Logger {
+	return &Logger{FieldLogger: l}
+}
+
+func (l *Logger) Debug(msg string, keyvals ...interface{}) {
+	l.WithFields(fields(keyvals)).Debug(msg)
+}
+func (l *Logger) Info(msg string, keyvals ...interface{}) {
+	l.WithFields(fields(keyvals)).Info(msg)
+}
+func (l *Logger) Warn(msg string, keyvals ...interface{}) {
+	l.WithFields(fields(keyvals)).Warn(msg)
+}
+func (l *Logger) Error(msg string, keyvals ...interface{}) {
+	l.WithFields(fields(keyvals)).Error(msg)
+}
+
+func fields(keyvals []interface{}) logrus.Fields {
+	f := make(logrus.Fields, len(keyvals)/2)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if key, ok := keyvals[i].(string); ok {
+			f[key] = keyvals[i+1]
+		}
+	}
+	return f
+}
+
+var _ curator.Logger = (*Logger)(nil)
@@ -0,0 +1,40 @@
+//Package stdlog adapts the standard library's *log.Logger to curator.Logger.
+package stdlog
+
+import (
+	curator "github.com/talbright/go-curator"
+
+	"fmt"
+	"log"
+)
+
+//Logger writes all curator log levels through a single *log.Logger,
+//prefixing the level name so output stays readable without structured
+//fields.
+type Logger struct {
+	*log.Logger
+}
+
+//New wraps l as a curator.Logger.
+func New(l *log.Logger) *Logger {
+	return &Logger{Logger: l}
+}
+
+func (l *Logger) Debug(msg string, keyvals ...interface{}) { l.log("DEBUG", msg, keyvals) }
+func (l *Logger) Info(msg string, keyvals ...interface{})  { l.log("INFO", msg, keyvals) }
+func (l *Logger) Warn(msg string, keyvals ...interface{})  { l.log("WARN", msg, keyvals) }
+func (l *Logger) Error(msg string, keyvals ...interface{}) { l.log("ERROR", msg, keyvals) }
+
+func (l *Logger) log(level, msg string, keyvals []interface{}) {
+	l.Printf("[%s] %s%s", level, msg, formatKeyvals(keyvals))
+}
+
+func formatKeyvals(keyvals []interface{}) string {
+	s := ""
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		s += fmt.Sprintf(" %v=%v", keyvals[i], keyvals[i+1])
+	}
+	return s
+}
+
+var _ curator.Logger = (*Logger)(nil)
@@ -0,0 +1,26 @@
+//Package zapadapter adapts a *zap.SugaredLogger to curator.Logger.
+package zapadapter
+
+import (
+	curator "github.com/talbright/go-curator"
+
+	"go.uber.org/zap"
+)
+
+//Logger wraps a *zap.SugaredLogger, passing keyvals straight through to
+//zap's own key/value logging methods.
+type Logger struct {
+	*zap.SugaredLogger
+}
+
+//New wraps l as a curator.Logger.
+func New(l *zap.SugaredLogger) *Logger {
+	return &Logger{SugaredLogger: l}
+}
+
+func (l *Logger) Debug(msg string, keyvals ...interface{}) { l.SugaredLogger.Debugw(msg, keyvals...) }
+func (l *Logger) Info(msg string, keyvals ...interface{})  { l.SugaredLogger.Infow(msg, keyvals...) }
+func (l *Logger) Warn(msg string, keyvals ...interface{})  { l.SugaredLogger.Warnw(msg, keyvals...) }
+func (l *Logger) Error(msg string, keyvals ...interface{}) { l.SugaredLogger.Errorw(msg, keyvals...) }
+
+var _ curator.Logger = (*Logger)(nil)
@@ -0,0 +1,61 @@
+package curator
+
+import (
+	"errors"
+	"strings"
+)
+
+//ErrPathNotFound occurs when a well-formed path does not exist in zk. It is
+//distinct from ErrInvalidPath, which means the path itself is malformed;
+//conflating the two (as WaitToExist previously did) makes it impossible to
+//tell a typo from a node that simply hasn't been created yet.
+var ErrPathNotFound = errors.New("path does not exist")
+
+//ValidatePath checks path against the same rules the zookeeper server
+//enforces: it must start with '/', must not end with '/' unless it is the
+//root, must not contain empty segments ("//"), must not contain '.' or '..'
+//segments, and must not contain null bytes or any of the reserved
+//control ranges U+0001-U+001F or U+007F-U+009F.
+func ValidatePath(path string) error {
+	if !strings.HasPrefix(path, "/") {
+		return ErrInvalidPath
+	}
+	if path == "/" {
+		return nil
+	}
+	if strings.HasSuffix(path, "/") {
+		return ErrInvalidPath
+	}
+	for _, r := range path {
+		switch {
+		case r == 0x00:
+			return ErrInvalidPath
+		case r >= 0x01 && r <= 0x1F:
+			return ErrInvalidPath
+		case r >= 0x7F && r <= 0x9F:
+			return ErrInvalidPath
+		}
+	}
+	for _, segment := range strings.Split(path, "/")[1:] {
+		switch segment {
+		case "", ".", "..":
+			return ErrInvalidPath
+		}
+	}
+	return nil
+}
+
+//NormalizePath rewrites path into the canonical form ValidatePath expects,
+//collapsing repeated slashes and trimming a trailing slash (other than the
+//root). It does not resolve '.' or '..' segments; zookeeper treats those as
+//invalid rather than as filesystem-style references, so ValidatePath
+//rejects them instead of NormalizePath silently resolving them away.
+func NormalizePath(path string) string {
+	var kept []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			kept = append(kept, segment)
+		}
+	}
+	return "/" + strings.Join(kept, "/")
+}
@@ -0,0 +1,47 @@
+package curator
+
+import "testing"
+
+func TestValidatePath(t *testing.T) {
+	cases := []struct {
+		path    string
+		wantErr error
+	}{
+		{"/", nil},
+		{"/a", nil},
+		{"/a/b", nil},
+		{"a/b", ErrInvalidPath},
+		{"/a/", ErrInvalidPath},
+		{"/a//b", ErrInvalidPath},
+		{"/a/./b", ErrInvalidPath},
+		{"/a/../b", ErrInvalidPath},
+		{"/a\x00b", ErrInvalidPath},
+		{"/a\x1fb", ErrInvalidPath},
+		{"/a\x7fb", ErrInvalidPath},
+	}
+	for _, c := range cases {
+		if err := ValidatePath(c.path); err != c.wantErr {
+			t.Errorf("ValidatePath(%q) = %v, want %v", c.path, err, c.wantErr)
+		}
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	cases := []struct {
+		path string
+		want string
+	}{
+		{"/", "/"},
+		{"/a", "/a"},
+		{"/a/b", "/a/b"},
+		{"/a//b", "/a/b"},
+		{"/a/b/", "/a/b"},
+		{"a/b", "/a/b"},
+		{"///", "/"},
+	}
+	for _, c := range cases {
+		if got := NormalizePath(c.path); got != c.want {
+			t.Errorf("NormalizePath(%q) = %q, want %q", c.path, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package plugin
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+//HashFunc hashes key to a uint64 used to place it on a consistent-hash Ring.
+type HashFunc func(key string) uint64
+
+//DefaultHashFunc hashes with FNV-1a, kept dependency-free on purpose.
+func DefaultHashFunc(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return h.Sum64()
+}
+
+//Strategy assigns work items to workers. Implementations should be
+//deterministic for a given set of workers/items so repeated calls converge
+//instead of churning assignments that haven't actually changed.
+type Strategy interface {
+	Assign(workers []string, items []string) map[string][]string
+}
+
+//Ring is a consistent-hash ring over a set of workers, with VirtualNodes
+//copies of each worker spread across the ring to smooth out load. A Ring is
+//safe for concurrent use: Build and Get may be called from different
+//goroutines, as happens when a ChildCache watch callback rebuilds the ring
+//while another goroutine is still reading from it.
+type Ring struct {
+	HashFunc     HashFunc
+	VirtualNodes int
+
+	mutex  sync.RWMutex
+	hashes []uint64
+	byHash map[uint64]string
+}
+
+//NewRing creates a Ring that hashes keys with fn (DefaultHashFunc if nil)
+//and places virtualNodes copies of each worker on the ring (100 if <= 0).
+func NewRing(fn HashFunc, virtualNodes int) *Ring {
+	if fn == nil {
+		fn = DefaultHashFunc
+	}
+	if virtualNodes <= 0 {
+		virtualNodes = 100
+	}
+	return &Ring{HashFunc: fn, VirtualNodes: virtualNodes}
+}
+
+//Build (re)populates the ring with workers, discarding any previous
+//membership. The new membership is computed before it is published, so
+//concurrent Get calls always see either the old or the new ring, never a
+//partially-built one.
+func (r *Ring) Build(workers []string) {
+	hashes := make([]uint64, 0, len(workers)*r.VirtualNodes)
+	byHash := make(map[uint64]string, len(workers)*r.VirtualNodes)
+	for _, worker := range workers {
+		for i := 0; i < r.VirtualNodes; i++ {
+			h := r.HashFunc(worker + "#" + strconv.Itoa(i))
+			hashes = append(hashes, h)
+			byHash[h] = worker
+		}
+	}
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+
+	r.mutex.Lock()
+	r.hashes = hashes
+	r.byHash = byHash
+	r.mutex.Unlock()
+}
+
+//Get returns the worker owning key, or "" if the ring has no members.
+func (r *Ring) Get(key string) string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if len(r.hashes) == 0 {
+		return ""
+	}
+	h := r.HashFunc(key)
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return r.byHash[r.hashes[i]]
+}
+
+//ConsistentHashStrategy assigns each item to the worker that owns it on a
+//consistent-hash Ring, so a worker joining or leaving only moves the items
+//whose ring ownership actually changed.
+type ConsistentHashStrategy struct {
+	Ring *Ring
+}
+
+//NewConsistentHashStrategy creates a ConsistentHashStrategy hashing keys
+//with fn and placing virtualNodes copies of each worker on the ring.
+func NewConsistentHashStrategy(fn HashFunc, virtualNodes int) *ConsistentHashStrategy {
+	return &ConsistentHashStrategy{Ring: NewRing(fn, virtualNodes)}
+}
+
+func (s *ConsistentHashStrategy) Assign(workers []string, items []string) map[string][]string {
+	assignment := make(map[string][]string, len(workers))
+	if len(workers) == 0 {
+		return assignment
+	}
+	s.Ring.Build(workers)
+	for _, item := range items {
+		worker := s.Ring.Get(item)
+		assignment[worker] = append(assignment[worker], item)
+	}
+	return assignment
+}
+
+//Rebalancer keeps a WorkerList's Work assignment converged with a Strategy.
+//Calling Rebalance after a worker joins or leaves (as observed on the
+//workers path's ChildCache) moves only the minimally-affected items,
+//applying the same Children.Remove/Add operations that back ShiftWork and
+//UnshiftWork rather than reshuffling everything by index. Rebalance calls
+//are serialized, since a ChildCache watch callback can fire a new one while
+//a previous convergence is still in flight.
+type Rebalancer struct {
+	Workers  *WorkerList
+	Strategy Strategy
+
+	mutex sync.Mutex
+}
+
+//NewRebalancer creates a Rebalancer converging items across workers using
+//strategy. A nil strategy defaults to consistent hashing with 100 virtual
+//nodes per worker.
+func NewRebalancer(workers *WorkerList, strategy Strategy) *Rebalancer {
+	if strategy == nil {
+		strategy = NewConsistentHashStrategy(nil, 100)
+	}
+	return &Rebalancer{Workers: workers, Strategy: strategy}
+}
+
+//Rebalance computes the desired assignment of items across the current
+//workers and converges each worker's children towards it, removing items it
+//no longer owns and adding items newly assigned to it.
+func (rb *Rebalancer) Rebalance(items []string) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	workers := rb.Workers.ToSlice()
+	ids := make([]string, len(workers))
+	for i, w := range workers {
+		ids[i] = w.Id()
+	}
+	desired := rb.Strategy.Assign(ids, items)
+
+	for i := range workers {
+		worker := workers[i]
+		current := make(map[string]Znode, worker.Children.Size())
+		for _, n := range worker.Children.ToSlice() {
+			current[n.Path] = n
+		}
+		wanted := make(map[string]bool, len(desired[worker.Id()]))
+		for _, path := range desired[worker.Id()] {
+			wanted[path] = true
+		}
+
+		for path, n := range current {
+			if wanted[path] {
+				continue
+			}
+			node := n
+			if err := worker.Children.Remove(&node); err != nil {
+				worker.client.Logger().Warn("unable to remove item during rebalance", "worker", worker.Id(), "path", node.Path, "error", err)
+			}
+		}
+
+		var toAdd []Znode
+		for path := range wanted {
+			if _, ok := current[path]; !ok {
+				toAdd = append(toAdd, *NewZnode(path))
+			}
+		}
+		worker.UnshiftWork(toAdd)
+	}
+}
@@ -0,0 +1,71 @@
+package plugin
+
+import "testing"
+
+func TestRingGet(t *testing.T) {
+	ring := NewRing(DefaultHashFunc, 10)
+	ring.Build([]string{"a", "b", "c"})
+
+	if got := ring.Get(""); got == "" {
+		t.Fatalf("Get on a built ring returned no owner")
+	}
+
+	first := ring.Get("item-1")
+	second := ring.Get("item-1")
+	if first != second {
+		t.Fatalf("Get is not deterministic for the same key: %q != %q", first, second)
+	}
+}
+
+func TestRingGetEmpty(t *testing.T) {
+	ring := NewRing(nil, 10)
+	if got := ring.Get("item-1"); got != "" {
+		t.Fatalf("Get on an empty ring = %q, want \"\"", got)
+	}
+}
+
+func TestConsistentHashStrategyAssign(t *testing.T) {
+	strategy := NewConsistentHashStrategy(DefaultHashFunc, 10)
+	workers := []string{"w1", "w2", "w3"}
+	items := []string{"i1", "i2", "i3", "i4", "i5", "i6"}
+
+	assignment := strategy.Assign(workers, items)
+
+	seen := make(map[string]bool, len(items))
+	for worker, assigned := range assignment {
+		found := false
+		for _, w := range workers {
+			if w == worker {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("Assign returned unknown worker %q", worker)
+		}
+		for _, item := range assigned {
+			if seen[item] {
+				t.Fatalf("item %q assigned to more than one worker", item)
+			}
+			seen[item] = true
+		}
+	}
+	if len(seen) != len(items) {
+		t.Fatalf("Assign covered %d of %d items", len(seen), len(items))
+	}
+
+	again := strategy.Assign(workers, items)
+	for worker, assigned := range assignment {
+		if len(again[worker]) != len(assigned) {
+			t.Fatalf("Assign is not stable across calls for worker %q: %v != %v", worker, assigned, again[worker])
+		}
+	}
+}
+
+func TestConsistentHashStrategyAssignNoWorkers(t *testing.T) {
+	strategy := NewConsistentHashStrategy(nil, 10)
+	assignment := strategy.Assign(nil, []string{"i1"})
+	if len(assignment) != 0 {
+		t.Fatalf("Assign with no workers returned %v, want empty", assignment)
+	}
+}
@@ -10,15 +10,17 @@ import (
 type Work struct {
 	Znode
 	Children *ChildCache
+	client   *Client
 }
 
 func (w Work) Id() string { return w.Path }
 
 func NewWork(client *Client, path string) *Work {
+	path = NormalizePath(path)
 	n := NewZnode(path)
 	cc := NewChildCache(client, path)
 	cc.CreateFlags = zk.FlagEphemeral
-	return &Work{Znode: *n, Children: cc}
+	return &Work{Znode: *n, Children: cc, client: client}
 }
 
 func (w Work) Spew() string {
@@ -28,15 +30,17 @@ func (w Work) Spew() string {
 type Worker struct {
 	Znode
 	Children *ChildCache
+	client   *Client
 }
 
 func (w Worker) Id() string { return w.Path }
 
 func NewWorker(client *Client, path string) *Worker {
+	path = NormalizePath(path)
 	n := NewZnode(path)
 	cc := NewChildCache(client, path)
 	cc.CreateFlags = zk.FlagEphemeral
-	return &Worker{Znode: *n, Children: cc}
+	return &Worker{Znode: *n, Children: cc, client: client}
 }
 
 func (w *Worker) ShiftWork(amount int) []Znode {
@@ -46,7 +50,7 @@ func (w *Worker) ShiftWork(amount int) []Znode {
 		for _, v := range w.Children.ToSlice()[0:amount] {
 			newNode := v
 			if err := w.Children.Remove(&newNode); err != nil {
-				//TODO: log.WithError(err).Warn("unable to remove worker")
+				w.client.Logger().Warn("unable to remove worker", "path", newNode.Path, "error", err)
 			}
 			removed = append(removed, newNode)
 		}
@@ -58,7 +62,7 @@ func (w *Worker) UnshiftWork(nodes []Znode) {
 	for _, n := range nodes {
 		newNode := n
 		if err := w.Children.Add(&newNode); err != nil {
-			//TODO: log.WithError(err).Warn("unable to add node")
+			w.client.Logger().Warn("unable to add node", "path", newNode.Path, "error", err)
 		}
 	}
 }
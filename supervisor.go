@@ -0,0 +1,146 @@
+package curator
+
+import (
+	"github.com/talbright/go-zookeeper/zk"
+
+	"time"
+)
+
+//SessionCallback is invoked by the supervisor when the client observes a
+//session state transition or successfully reconnects.
+type SessionCallback func(c *Client)
+
+//WithSessionEstablishedCallback registers a callback invoked whenever the
+//client obtains a zk session (zk.StateHasSession) observed by Supervise,
+//including every reconnect. The first connect's session event is only seen
+//if Connect did not already consume it, which it does when
+//Settings.ZkWaitForSession is set.
+func WithSessionEstablishedCallback(cb SessionCallback) ClientOption {
+	return func(c *Client) {
+		c.onHasSession = append(c.onHasSession, cb)
+	}
+}
+
+//WithSessionExpiredCallback registers a callback invoked when the client's
+//session expires (zk.StateExpired). Consumers that keep ephemeral znodes
+//(e.g. plugin.Worker) should use this to discard state that vanished with
+//the session.
+func WithSessionExpiredCallback(cb SessionCallback) ClientOption {
+	return func(c *Client) {
+		c.onExpired = append(c.onExpired, cb)
+	}
+}
+
+//WithReconnectCallback registers a callback invoked after Supervise has
+//re-dialed zookeeper and the client has a fresh session, giving consumers a
+//chance to re-create ephemeral state that was lost.
+func WithReconnectCallback(cb SessionCallback) ClientOption {
+	return func(c *Client) {
+		c.onReconnect = append(c.onReconnect, cb)
+	}
+}
+
+func (c *Client) fire(callbacks []SessionCallback) {
+	for _, cb := range callbacks {
+		cb(c)
+	}
+}
+
+//Supervise watches evnt (the channel returned by Connect) and drives the
+//registered session callbacks. If the connection is lost it redials using
+//the settings and options passed to the original Connect call, retrying
+//with exponential backoff until zk.Connect succeeds, then keeps watching
+//the new connection's event channel for its session.
+//Supervise runs until Close is called or StopSupervising is invoked; callers
+//should invoke it once, right after Connect.
+func (c *Client) Supervise(evnt <-chan zk.Event) {
+	c.sessionMu.Lock()
+	c.superviseStop = make(chan struct{})
+	c.superviseDone = make(chan struct{})
+	stop := c.superviseStop
+	done := c.superviseDone
+	c.sessionMu.Unlock()
+
+	go func() {
+		defer close(done)
+		reconnecting := false
+		for {
+			select {
+			case <-stop:
+				return
+			case event, ok := <-evnt:
+				if !ok {
+					return
+				}
+				if event.Type != zk.EventSession {
+					continue
+				}
+				switch event.State {
+				case zk.StateConnected:
+					c.fire(c.onConnected)
+				case zk.StateHasSession:
+					if reconnecting {
+						c.fire(c.onReconnect)
+						reconnecting = false
+					}
+					c.fire(c.onHasSession)
+				case zk.StateDisconnected:
+					c.fire(c.onDisconnected)
+				case zk.StateExpired:
+					c.fire(c.onExpired)
+					reconnecting = true
+					evnt = c.reconnect(stop)
+				case zk.StateAuthFailed:
+					c.fire(c.onAuthFailed)
+				}
+			}
+		}
+	}()
+}
+
+//StopSupervising stops the goroutine started by Supervise and blocks until
+//it has exited.
+func (c *Client) StopSupervising() {
+	c.sessionMu.Lock()
+	stop := c.superviseStop
+	done := c.superviseDone
+	c.sessionMu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+//reconnect re-dials zookeeper with an exponential backoff, returning the new
+//event channel as soon as zk.Connect succeeds, or nil if stop fires first.
+//The new session is reported later as a zk.StateHasSession event on that
+//channel.
+func (c *Client) reconnect(stop <-chan struct{}) <-chan zk.Event {
+	wait := 500 * time.Millisecond
+	const maxWait = 30 * time.Second
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(wait):
+		}
+		c.logger.Debug("reconnecting to zookeeper", "servers", c.settings.ZkServers)
+		conn, evnt, err := zk.Connect(c.settings.ZkServers, c.settings.ZkSessionTimeout, c.connOptions...)
+		if err != nil {
+			c.logger.Warn("reconnect attempt failed", "error", err, "retry_in", wait)
+			if wait *= 2; wait > maxWait {
+				wait = maxWait
+			}
+			continue
+		}
+		c.mutex.Lock()
+		oldConn := c.Conn
+		c.Conn = conn
+		c.mutex.Unlock()
+		if oldConn != nil {
+			oldConn.Close()
+		}
+		return evnt
+	}
+}